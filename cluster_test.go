@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/moneyforward/mf-common-go/ratelimit"
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis Cluster correctness matrix: re-runs the single-node BurstThenDeny /
+// RefillsOverTime / IsolatedPerKey suites against a redis.ClusterClient, plus
+// a slot invariant check so callers know the limiter's Lua script never
+// touches more than one key per call (which would CROSSSLOT-fail under
+// cluster mode). Skips cleanly when REDIS_CLUSTER_ADDRS isn't set.
+
+// newClusterLimiter mirrors newLimiter (main_test.go) but for a
+// *redis.ClusterClient. ratelimit.NewRedisRateLimiter's real parameter type
+// can't be confirmed from this checkout — mf-common-go isn't vendored here,
+// and the module proxy configured for this environment 404s on it — so this
+// assumes it accepts redis.UniversalClient, the convention used by
+// go-redis-ecosystem limiters such as redis_rate/v10's NewLimiter. That
+// assumption is deliberately kept out of newLimiter itself (which stays on
+// the concrete *redis.Client every other test already relies on): if it's
+// wrong, only this file and the cluster suite it drives (already
+// self-skipping without REDIS_CLUSTER_ADDRS) fail to compile, not the
+// single-node tests. Confirm against the real dependency before relying on
+// this in CI.
+func newClusterLimiter(t *testing.T, rdb *redis.ClusterClient, prefix string, ratePerSec, capacity int, ttl time.Duration) ratelimit.RateLimiter {
+	t.Helper()
+	limiter, err := ratelimit.NewRedisRateLimiter(
+		rdb,
+		ratelimit.WithRatePerSecond(float64(ratePerSec)),
+		ratelimit.WithCapacity(float64(capacity)),
+		ratelimit.WithTTL(ttl),
+		ratelimit.WithPrefix(prefix),
+	)
+	if err != nil {
+		t.Fatalf("NewRedisRateLimiter error: %v", err)
+	}
+	return limiter
+}
+
+func TestRedisRateLimiter_Cluster_BurstThenDeny(t *testing.T) {
+	rdb := newRedisClusterClient()
+	t.Cleanup(func() { _ = rdb.Close() })
+	requireRedisCluster(t, rdb)
+
+	const (
+		rate     = 1
+		capacity = 3
+	)
+	limiter := newClusterLimiter(t, rdb, uniquePrefix(t), rate, capacity, 5*time.Second)
+	assertBurstThenDeny(t, limiter, capacity)
+}
+
+func TestRedisRateLimiter_Cluster_RefillsOverTime(t *testing.T) {
+	rdb := newRedisClusterClient()
+	t.Cleanup(func() { _ = rdb.Close() })
+	requireRedisCluster(t, rdb)
+
+	const (
+		rate     = 2 // tokens/sec
+		capacity = 2
+	)
+	limiter := newClusterLimiter(t, rdb, uniquePrefix(t), rate, capacity, 5*time.Second)
+	assertRefillsOverTime(t, limiter, capacity)
+}
+
+func TestRedisRateLimiter_Cluster_IsolatedPerKey(t *testing.T) {
+	rdb := newRedisClusterClient()
+	t.Cleanup(func() { _ = rdb.Close() })
+	requireRedisCluster(t, rdb)
+
+	const (
+		rate     = 1
+		capacity = 2
+	)
+	limiter := newClusterLimiter(t, rdb, uniquePrefix(t), rate, capacity, 5*time.Second)
+	assertIsolatedPerKey(t, limiter, capacity)
+}
+
+// TestRedisRateLimiter_Cluster_KeySlot asserts that limiter keys sharing a
+// Redis Cluster hash tag in their prefix (e.g. "rl:{user:42}:") always hash
+// to the same slot, so a caller composing several limiters (per-minute,
+// per-hour, ...) for one logical entity can keep them co-located and safe for
+// any future multi-key script.
+func TestRedisRateLimiter_Cluster_KeySlot(t *testing.T) {
+	rdb := newRedisClusterClient()
+	t.Cleanup(func() { _ = rdb.Close() })
+	requireRedisCluster(t, rdb)
+
+	ctx := context.Background()
+	tag := uniquePrefix(t)
+	prefix := "rl:{" + tag + "}:"
+	keys := []string{prefix + "minute", prefix + "hour", prefix + "burst"}
+
+	var wantSlot int64 = -1
+	for _, key := range keys {
+		slot, err := rdb.ClusterKeySlot(ctx, key).Result()
+		if err != nil {
+			t.Fatalf("ClusterKeySlot(%q) error: %v", key, err)
+		}
+		if wantSlot == -1 {
+			wantSlot = slot
+			continue
+		}
+		if slot != wantSlot {
+			t.Fatalf("key %q hashed to slot %d, want %d (same hash tag as the other keys)", key, slot, wantSlot)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"runtime"
 	"strconv"
@@ -18,6 +19,10 @@ import (
 
 type allowFunc func(ctx context.Context, key string) (allowed bool, remaining int, err error)
 
+// allowMultiFunc checks several limiter keys for one logical request (e.g.
+// per-user, per-tenant, per-endpoint) and reports each key's own result.
+type allowMultiFunc func(ctx context.Context, keys []string) (allowed []bool, remaining []int, err error)
+
 type segment struct {
 	dur time.Duration
 	rps int
@@ -26,19 +31,36 @@ type segment struct {
 type scenario struct {
 	name     string
 	segments []segment
+	// keysPerRequest, when > 0, runs this scenario through runMultiScenario
+	// instead of runScenario: each simulated request checks this many limiter
+	// keys at once rather than just one.
+	keysPerRequest int
 }
 
 type loadStats struct {
-	total     int64
-	allowed   int64
-	denied    int64
-	errs      int64
-	duration  time.Duration
-	maxHeap   uint64
-	startHeap uint64
-	endHeap   uint64
-	allocDiff uint64
-	gcsDiff   uint32
+	total      int64
+	allowed    int64
+	denied     int64
+	errs       int64
+	duration   time.Duration
+	maxHeap    uint64
+	startHeap  uint64
+	endHeap    uint64
+	allocDiff  uint64
+	gcsDiff    uint32
+	p50        time.Duration
+	p95        time.Duration
+	p99        time.Duration
+	p999       time.Duration
+	maxLatency time.Duration
+}
+
+// comparisonRow pairs one (impl, scenario) run with its collected stats; used
+// both for the in-log table and the CSV artifact.
+type comparisonRow struct {
+	impl     string
+	scenario string
+	stats    loadStats
 }
 
 func envInt(name string, def int) int {
@@ -76,6 +98,120 @@ func newMFCommonAllow(t testing.TB, rdb *redis.Client, prefix string, ratePerSec
 	}
 }
 
+// gcraTimeHelpers is shared by gcraLuaScript and gcraLuaMultiScript. Lua 5.1
+// numbers are float64, which only has ~15-17 significant decimal digits of
+// precision; a Unix-nanosecond timestamp (~1.77e18) already exceeds that, so
+// tracking "now"/TAT as one such number and round-tripping it through
+// tostring/tonumber (which formats via the default %.14g) silently rounds off
+// tens of thousands of nanoseconds. These helpers instead track every
+// timestamp as a (seconds, nanosecond-remainder) pair — each half stays many
+// orders of magnitude below 2^53, so both the arithmetic and the
+// string(sec)+":"+string(nsec) persisted form are exact.
+const gcraTimeHelpers = `
+local function ts_cmp(a_sec, a_nsec, b_sec, b_nsec)
+	if a_sec ~= b_sec then
+		if a_sec > b_sec then return 1 else return -1 end
+	end
+	if a_nsec > b_nsec then return 1 elseif a_nsec < b_nsec then return -1 else return 0 end
+end
+
+local function ts_add_ns(sec, nsec, delta_ns)
+	local total = nsec + delta_ns
+	local carry = math.floor(total / 1e9)
+	local rem = total - carry * 1e9
+	if rem < 0 then
+		carry = carry - 1
+		rem = rem + 1e9
+	end
+	return sec + carry, math.floor(rem + 0.5)
+end
+
+local function ts_diff_ns(a_sec, a_nsec, b_sec, b_nsec)
+	return (a_sec - b_sec) * 1e9 + (a_nsec - b_nsec)
+end
+
+local function ts_encode(sec, nsec)
+	return string.format("%d:%d", sec, nsec)
+end
+
+local function ts_decode(raw)
+	local sep = string.find(raw, ":")
+	return tonumber(string.sub(raw, 1, sep - 1)), tonumber(string.sub(raw, sep + 1))
+end
+`
+
+// gcraLuaScript implements the Generic Cell Rate Algorithm entirely in Lua so a
+// single round trip (via EVALSHA, falling back to EVAL on NOSCRIPT) decides the
+// outcome. It stores one key per limiter key holding the TAT (theoretical
+// arrival time) as a "seconds:nanoseconds" pair (see gcraTimeHelpers) rather
+// than a single nanosecond-epoch number, so it round-trips exactly.
+//
+// KEYS[1] = limiter key
+// ARGV[1] = rate (tokens/sec)
+// ARGV[2] = burst (capacity)
+// ARGV[3] = margin in milliseconds added to the PEXPIRE so the key outlives
+//
+//	the window it protects
+//
+// Returns {allowed (0/1), remaining}.
+var gcraLuaScript = redis.NewScript(gcraTimeHelpers + `
+local time_parts = redis.call('TIME')
+local now_sec = tonumber(time_parts[1])
+local now_nsec = tonumber(time_parts[2]) * 1e3
+
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local margin_ms = tonumber(ARGV[3])
+
+local emission_interval = 1e9 / rate
+local delay_tolerance = emission_interval * burst
+
+local tat_sec, tat_nsec
+local raw = redis.call('GET', KEYS[1])
+if raw then
+	tat_sec, tat_nsec = ts_decode(raw)
+else
+	tat_sec, tat_nsec = now_sec, now_nsec
+end
+
+local base_sec, base_nsec = tat_sec, tat_nsec
+if ts_cmp(now_sec, now_nsec, tat_sec, tat_nsec) > 0 then
+	base_sec, base_nsec = now_sec, now_nsec
+end
+
+local new_tat_sec, new_tat_nsec = ts_add_ns(base_sec, base_nsec, emission_interval)
+local allow_at_sec, allow_at_nsec = ts_add_ns(new_tat_sec, new_tat_nsec, -delay_tolerance)
+local remaining = math.floor((delay_tolerance - ts_diff_ns(new_tat_sec, new_tat_nsec, now_sec, now_nsec)) / emission_interval)
+
+if ts_cmp(now_sec, now_nsec, allow_at_sec, allow_at_nsec) >= 0 then
+	local ttl_ms = math.floor(delay_tolerance / 1e6) + margin_ms
+	redis.call('SET', KEYS[1], ts_encode(new_tat_sec, new_tat_nsec), 'PX', ttl_ms)
+	return {1, remaining}
+end
+
+return {0, remaining}
+`)
+
+// gcraPexpireMarginMs pads the key's PEXPIRE beyond its delay tolerance so it
+// never expires mid-window and resets a key that's still being throttled.
+const gcraPexpireMarginMs = 1000
+
+func newGCRALuaAllow(rdb *redis.Client, ratePerSec, capacity int) allowFunc {
+	return func(ctx context.Context, key string) (bool, int, error) {
+		res, err := gcraLuaScript.Run(ctx, rdb, []string{key}, ratePerSec, capacity, gcraPexpireMarginMs).Result()
+		if err != nil {
+			return false, 0, err
+		}
+		vals, ok := res.([]interface{})
+		if !ok || len(vals) != 2 {
+			return false, 0, fmt.Errorf("gcra-lua: unexpected script result %#v", res)
+		}
+		allowed := vals[0].(int64) == 1
+		remaining := int(vals[1].(int64))
+		return allowed, remaining, nil
+	}
+}
+
 func newRedisRateAllow(rdb *redis.Client, ratePerSec, capacity int) allowFunc {
 	limiter := redis_rate.NewLimiter(rdb)
 	limit := redis_rate.Limit{
@@ -99,11 +235,117 @@ func warmup(ctx context.Context, af allowFunc, key string, n int) {
 	}
 }
 
-func runScenario(ctx context.Context, af allowFunc, key string, segments []segment, concurrency int) loadStats {
+func warmupMulti(ctx context.Context, amf allowMultiFunc, keys []string, n int) {
+	for i := 0; i < n; i++ {
+		_, _, _ = amf(ctx, keys)
+	}
+}
+
+// newSequentialAllowMulti adapts a single-key allowFunc into an allowMultiFunc
+// via the naive N-round-trip loop, i.e. what a caller gets today with
+// mf-common-go or redis_rate/v10 since neither exposes a batched Allow.
+func newSequentialAllowMulti(af allowFunc) allowMultiFunc {
+	return func(ctx context.Context, keys []string) ([]bool, []int, error) {
+		allowed := make([]bool, len(keys))
+		remaining := make([]int, len(keys))
+		for i, key := range keys {
+			a, r, err := af(ctx, key)
+			if err != nil {
+				return allowed, remaining, err
+			}
+			allowed[i] = a
+			remaining[i] = r
+		}
+		return allowed, remaining, nil
+	}
+}
+
+// gcraLuaMultiScript is gcraLuaScript generalized to KEYS[1..N]: one round
+// trip decides every key's GCRA outcome instead of N sequential calls. Same
+// "seconds:nanoseconds" TAT encoding as gcraLuaScript (see gcraTimeHelpers)
+// so it doesn't lose precision at nanosecond-epoch magnitudes either.
+//
+// ARGV[1] = rate (tokens/sec)
+// ARGV[2] = burst (capacity)
+// ARGV[3] = margin in milliseconds added to the PEXPIRE
+//
+// Returns a flat {allowed_1, remaining_1, allowed_2, remaining_2, ...} array,
+// one pair per KEYS entry, in order.
+var gcraLuaMultiScript = redis.NewScript(gcraTimeHelpers + `
+local time_parts = redis.call('TIME')
+local now_sec = tonumber(time_parts[1])
+local now_nsec = tonumber(time_parts[2]) * 1e3
+
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local margin_ms = tonumber(ARGV[3])
+
+local emission_interval = 1e9 / rate
+local delay_tolerance = emission_interval * burst
+
+local results = {}
+for i = 1, #KEYS do
+	local tat_sec, tat_nsec
+	local raw = redis.call('GET', KEYS[i])
+	if raw then
+		tat_sec, tat_nsec = ts_decode(raw)
+	else
+		tat_sec, tat_nsec = now_sec, now_nsec
+	end
+
+	local base_sec, base_nsec = tat_sec, tat_nsec
+	if ts_cmp(now_sec, now_nsec, tat_sec, tat_nsec) > 0 then
+		base_sec, base_nsec = now_sec, now_nsec
+	end
+
+	local new_tat_sec, new_tat_nsec = ts_add_ns(base_sec, base_nsec, emission_interval)
+	local allow_at_sec, allow_at_nsec = ts_add_ns(new_tat_sec, new_tat_nsec, -delay_tolerance)
+	local remaining = math.floor((delay_tolerance - ts_diff_ns(new_tat_sec, new_tat_nsec, now_sec, now_nsec)) / emission_interval)
+
+	if ts_cmp(now_sec, now_nsec, allow_at_sec, allow_at_nsec) >= 0 then
+		local ttl_ms = math.floor(delay_tolerance / 1e6) + margin_ms
+		redis.call('SET', KEYS[i], ts_encode(new_tat_sec, new_tat_nsec), 'PX', ttl_ms)
+		table.insert(results, 1)
+	else
+		table.insert(results, 0)
+	end
+	table.insert(results, remaining)
+end
+
+return results
+`)
+
+func newGCRALuaAllowMulti(rdb *redis.Client, ratePerSec, capacity int) allowMultiFunc {
+	return func(ctx context.Context, keys []string) ([]bool, []int, error) {
+		allowed := make([]bool, len(keys))
+		remaining := make([]int, len(keys))
+
+		res, err := gcraLuaMultiScript.Run(ctx, rdb, keys, ratePerSec, capacity, gcraPexpireMarginMs).Result()
+		if err != nil {
+			return allowed, remaining, err
+		}
+		vals, ok := res.([]interface{})
+		if !ok || len(vals) != 2*len(keys) {
+			return allowed, remaining, fmt.Errorf("gcra-lua-multi: unexpected script result %#v", res)
+		}
+		for i := range keys {
+			allowed[i] = vals[2*i].(int64) == 1
+			remaining[i] = int(vals[2*i+1].(int64))
+		}
+		return allowed, remaining, nil
+	}
+}
+
+// runLoadTest paces concurrency workers through segments, calling do once per
+// paced tick and folding the returned per-call outcomes (one bool per key
+// checked, allowed=true/false; err non-nil for a failed call) into the
+// returned loadStats, alongside heap and latency sampling. runScenario and
+// runMultiScenario are both thin wrappers around this: the only thing that
+// differs between a single-key allowFunc and a multi-key allowMultiFunc is
+// what one "call" looks like, which is exactly what do captures.
+func runLoadTest(ctx context.Context, segments []segment, concurrency int, do func(ctx context.Context) (oks []bool, err error)) loadStats {
 	var total, allowed, denied, errs int64
 
-	// Warm up once to load scripts / prime clients, then GC so the measured heap looks sane.
-	warmup(ctx, af, key, 100)
 	runtime.GC()
 
 	var msBefore runtime.MemStats
@@ -137,11 +379,16 @@ func runScenario(ctx context.Context, af allowFunc, key string, segments []segme
 
 	start := time.Now()
 
+	var maxLatencyNs int64
+	reservoirs := make([]*latencyReservoir, concurrency)
+
 	var wg sync.WaitGroup
 	wg.Add(concurrency)
 	for w := 0; w < concurrency; w++ {
+		reservoirs[w] = newLatencyReservoir(start.UnixNano() + int64(w))
 		go func(worker int) {
 			defer wg.Done()
+			reservoir := reservoirs[worker]
 			for _, seg := range segments {
 				if seg.rps <= 0 || seg.dur <= 0 {
 					continue
@@ -173,16 +420,28 @@ func runScenario(ctx context.Context, af allowFunc, key string, segments []segme
 						time.Sleep(d)
 					}
 
-					ok, _, err := af(ctx, key)
-					atomic.AddInt64(&total, 1)
+					callStart := time.Now()
+					oks, err := do(ctx)
+					elapsed := time.Since(callStart)
+					reservoir.record(elapsed)
+					atomicMaxDuration(&maxLatencyNs, elapsed)
+
 					if err != nil {
-						atomic.AddInt64(&errs, 1)
+						n := int64(len(oks))
+						if n == 0 {
+							n = 1
+						}
+						atomic.AddInt64(&total, n)
+						atomic.AddInt64(&errs, n)
 						continue
 					}
-					if ok {
-						atomic.AddInt64(&allowed, 1)
-					} else {
-						atomic.AddInt64(&denied, 1)
+					for _, ok := range oks {
+						atomic.AddInt64(&total, 1)
+						if ok {
+							atomic.AddInt64(&allowed, 1)
+						} else {
+							atomic.AddInt64(&denied, 1)
+						}
 					}
 				}
 			}
@@ -197,17 +456,105 @@ func runScenario(ctx context.Context, af allowFunc, key string, segments []segme
 	var msAfter runtime.MemStats
 	runtime.ReadMemStats(&msAfter)
 
+	merged := mergeLatencySamples(reservoirs)
+
 	return loadStats{
-		total:     total,
-		allowed:   allowed,
-		denied:    denied,
-		errs:      errs,
-		duration:  dur,
-		maxHeap:   atomic.LoadUint64(&maxHeap),
-		startHeap: msBefore.HeapAlloc,
-		endHeap:   msAfter.HeapAlloc,
-		allocDiff: msAfter.TotalAlloc - msBefore.TotalAlloc,
-		gcsDiff:   msAfter.NumGC - msBefore.NumGC,
+		total:      total,
+		allowed:    allowed,
+		denied:     denied,
+		errs:       errs,
+		duration:   dur,
+		maxHeap:    atomic.LoadUint64(&maxHeap),
+		startHeap:  msBefore.HeapAlloc,
+		endHeap:    msAfter.HeapAlloc,
+		allocDiff:  msAfter.TotalAlloc - msBefore.TotalAlloc,
+		gcsDiff:    msAfter.NumGC - msBefore.NumGC,
+		p50:        latencyPercentile(merged, 0.50),
+		p95:        latencyPercentile(merged, 0.95),
+		p99:        latencyPercentile(merged, 0.99),
+		p999:       latencyPercentile(merged, 0.999),
+		maxLatency: time.Duration(atomic.LoadInt64(&maxLatencyNs)),
+	}
+}
+
+func runScenario(ctx context.Context, af allowFunc, key string, segments []segment, concurrency int) loadStats {
+	// Warm up once to load scripts / prime clients, then GC so the measured heap looks sane.
+	warmup(ctx, af, key, 100)
+
+	return runLoadTest(ctx, segments, concurrency, func(ctx context.Context) ([]bool, error) {
+		ok, _, err := af(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		return []bool{ok}, nil
+	})
+}
+
+// runMultiScenario mirrors runScenario but drives an allowMultiFunc: each
+// simulated request checks every key in keys in one call, and total/allowed/
+// denied count each key's own result rather than one per request.
+func runMultiScenario(ctx context.Context, amf allowMultiFunc, keys []string, segments []segment, concurrency int) loadStats {
+	warmupMulti(ctx, amf, keys, 100)
+
+	return runLoadTest(ctx, segments, concurrency, func(ctx context.Context) ([]bool, error) {
+		oks, _, err := amf(ctx, keys)
+		if err != nil {
+			// runLoadTest counts total/errs by len(oks); report one per key
+			// checked, not zero, so a failed pipelined call is weighted the
+			// same as len(keys) failed single-key calls would have been.
+			return make([]bool, len(keys)), err
+		}
+		return oks, nil
+	})
+}
+
+// runMultiKeyScenario drives a scenario with keysPerRequest > 0: mf-common-go
+// and redis_rate/v10 only expose a single-key Allow, so they're measured as
+// the naive N-round-trip loop; gcra-lua gets both that naive loop and a true
+// pipelined (single round trip, KEYS[1..N]) variant so the two can be
+// compared side by side.
+func runMultiKeyScenario(t *testing.T, rdb *redis.Client, sc scenario, ratePerSec, capacity int, ttl, duration time.Duration, rows *[]comparisonRow) {
+	t.Helper()
+	concurrency := envInt("CONCURRENCY", 50)
+
+	run := func(implName string, amf allowMultiFunc, keys []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), duration+10*time.Second)
+		t.Logf("running: impl=%s scenario=%s", implName, sc.name)
+
+		stats := runMultiScenario(ctx, amf, keys, sc.segments, concurrency)
+		cancel()
+		*rows = append(*rows, comparisonRow{impl: implName, scenario: sc.name, stats: stats})
+		t.Logf("done:    impl=%s scenario=%s total=%d allowed=%d denied=%d errs=%d dur=%s",
+			implName, sc.name, stats.total, stats.allowed, stats.denied, stats.errs, stats.duration,
+		)
+	}
+
+	keysFor := func(prefix string) []string {
+		keys := make([]string, sc.keysPerRequest)
+		for i := range keys {
+			keys[i] = fmt.Sprintf("%suser:bench:%d", prefix, i)
+		}
+		return keys
+	}
+
+	{
+		prefix := uniquePrefix(t) + "mf:" + sc.name + ":"
+		af := newMFCommonAllow(t, rdb, prefix, ratePerSec, capacity, ttl)
+		run("mf-common-go-naive-loop", newSequentialAllowMulti(af), keysFor(prefix))
+	}
+	{
+		prefix := uniquePrefix(t) + "rr:" + sc.name + ":"
+		af := newRedisRateAllow(rdb, ratePerSec, capacity)
+		run("redis_rate/v10-naive-loop", newSequentialAllowMulti(af), keysFor(prefix))
+	}
+	{
+		prefix := uniquePrefix(t) + "gcra-naive:" + sc.name + ":"
+		af := newGCRALuaAllow(rdb, ratePerSec, capacity)
+		run("gcra-lua-naive-loop", newSequentialAllowMulti(af), keysFor(prefix))
+	}
+	{
+		prefix := uniquePrefix(t) + "gcra-pipelined:" + sc.name + ":"
+		run("gcra-lua-pipelined", newGCRALuaAllowMulti(rdb, ratePerSec, capacity), keysFor(prefix))
 	}
 }
 
@@ -252,6 +599,13 @@ func TestComparison_redis_rate_vs_mf_common_go(t *testing.T) {
 			name:     "burst_1000rps_20s",
 			segments: []segment{{dur: duration, rps: burstRPS}},
 		},
+		{
+			// A gateway checking several limiter keys per request (per-user,
+			// per-tenant, per-endpoint) instead of just one.
+			name:           "multi_key_5per_req_1000rps_20s",
+			segments:       []segment{{dur: duration, rps: burstRPS}},
+			keysPerRequest: 5,
+		},
 	}
 
 	rdb := newRedisClient()
@@ -262,14 +616,13 @@ func TestComparison_redis_rate_vs_mf_common_go(t *testing.T) {
 		ratePerSec, capacity, concurrency, duration, slowRPS, slowDur, burstRPS,
 	)
 
-	type row struct {
-		impl     string
-		scenario string
-		stats    loadStats
-	}
-	var rows []row
+	var rows []comparisonRow
 
 	for _, sc := range scenarios {
+		if sc.keysPerRequest > 0 {
+			runMultiKeyScenario(t, rdb, sc, ratePerSec, capacity, ttl, duration, &rows)
+			continue
+		}
 		// mf-common-go
 		{
 			ctx, cancel := context.WithTimeout(context.Background(), duration+10*time.Second)
@@ -280,7 +633,7 @@ func TestComparison_redis_rate_vs_mf_common_go(t *testing.T) {
 			af := newMFCommonAllow(t, rdb, prefix, ratePerSec, capacity, ttl)
 			stats := runScenario(ctx, af, key, sc.segments, concurrency)
 			cancel()
-			rows = append(rows, row{impl: "mf-common-go", scenario: sc.name, stats: stats})
+			rows = append(rows, comparisonRow{impl: "mf-common-go", scenario: sc.name, stats: stats})
 			t.Logf("done:    impl=%s scenario=%s total=%d allowed=%d denied=%d errs=%d dur=%s",
 				"mf-common-go", sc.name, stats.total, stats.allowed, stats.denied, stats.errs, stats.duration,
 			)
@@ -295,11 +648,45 @@ func TestComparison_redis_rate_vs_mf_common_go(t *testing.T) {
 			af := newRedisRateAllow(rdb, ratePerSec, capacity)
 			stats := runScenario(ctx, af, key, sc.segments, concurrency)
 			cancel()
-			rows = append(rows, row{impl: "redis_rate/v10", scenario: sc.name, stats: stats})
+			rows = append(rows, comparisonRow{impl: "redis_rate/v10", scenario: sc.name, stats: stats})
 			t.Logf("done:    impl=%s scenario=%s total=%d allowed=%d denied=%d errs=%d dur=%s",
 				"redis_rate/v10", sc.name, stats.total, stats.allowed, stats.denied, stats.errs, stats.duration,
 			)
 		}
+		// gcra-lua: same GCRA as redis_rate/v10 but built in this repo as a single
+		// EVALSHA'd Lua script, to separate "algorithm" from "client library" effects.
+		{
+			ctx, cancel := context.WithTimeout(context.Background(), duration+10*time.Second)
+			t.Logf("running: impl=%s scenario=%s", "gcra-lua", sc.name)
+
+			prefix := uniquePrefix(t) + "gcra:" + sc.name + ":"
+			key := prefix + "user:bench"
+			af := newGCRALuaAllow(rdb, ratePerSec, capacity)
+			stats := runScenario(ctx, af, key, sc.segments, concurrency)
+			cancel()
+			rows = append(rows, comparisonRow{impl: "gcra-lua", scenario: sc.name, stats: stats})
+			t.Logf("done:    impl=%s scenario=%s total=%d allowed=%d denied=%d errs=%d dur=%s",
+				"gcra-lua", sc.name, stats.total, stats.allowed, stats.denied, stats.errs, stats.duration,
+			)
+		}
+		// mf-common-go-local-cache: same workload, fronted by an in-process
+		// LRU of recently-denied keys (see newLocalCacheAllow). Only run
+		// under the heaviest-contention scenario, where most calls after the
+		// burst is exhausted are denies this should shortcut locally.
+		if sc.name == "burst_1000rps_20s" {
+			ctx, cancel := context.WithTimeout(context.Background(), duration+10*time.Second)
+			t.Logf("running: impl=%s scenario=%s", "mf-common-go-local-cache", sc.name)
+
+			prefix := uniquePrefix(t) + "mf-lc:" + sc.name + ":"
+			key := "user:bench"
+			af := newLocalCacheAllow(newMFCommonAllow(t, rdb, prefix, ratePerSec, capacity, ttl), 10_000, 50*time.Millisecond)
+			stats := runScenario(ctx, af, key, sc.segments, concurrency)
+			cancel()
+			rows = append(rows, comparisonRow{impl: "mf-common-go-local-cache", scenario: sc.name, stats: stats})
+			t.Logf("done:    impl=%s scenario=%s total=%d allowed=%d denied=%d errs=%d dur=%s",
+				"mf-common-go-local-cache", sc.name, stats.total, stats.allowed, stats.denied, stats.errs, stats.duration,
+			)
+		}
 	}
 
 	// Print a simple table (kept in logs so it doesn’t fail CI due to formatting).
@@ -332,6 +719,23 @@ func TestComparison_redis_rate_vs_mf_common_go(t *testing.T) {
 		t.Logf("%-14s | %-22s | %12d", r.impl, r.scenario, r.stats.allocDiff)
 	}
 
+	// Per-request latency distribution: the signal that matters most when
+	// comparing limiters under burst, since throughput alone hides tail cost.
+	t.Logf("")
+	t.Logf("%-14s | %-22s | %9s | %9s | %9s | %9s | %9s", "impl", "scenario", "p50", "p95", "p99", "p999", "max")
+	t.Log(strings.Repeat("-", 80))
+	for _, r := range rows {
+		t.Logf("%-14s | %-22s | %9s | %9s | %9s | %9s | %9s",
+			r.impl, r.scenario, r.stats.p50, r.stats.p95, r.stats.p99, r.stats.p999, r.stats.maxLatency,
+		)
+	}
+
+	if err := writeLatencyCSV("latency_results.csv", rows); err != nil {
+		t.Errorf("writeLatencyCSV: %v", err)
+	} else {
+		t.Logf("wrote latency_results.csv")
+	}
+
 	// Small sanity check to catch totally broken runs.
 	for _, r := range rows {
 		if r.stats.total == 0 {
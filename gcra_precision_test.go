@@ -0,0 +1,201 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+// This file is the reproducible harness behind the TAT-encoding fix in
+// gcraLuaScript/gcraLuaMultiScript (see gcraTimeHelpers): it reimplements
+// both the pre-fix single-float TAT (lossy tostring round-trip) and the
+// fixed (seconds, nanosecond-remainder) pair in plain Go, then replays many
+// simulated call traces through both and asserts they never disagree on an
+// allow/deny decision or a remaining count.
+
+// oldGCRADecision mirrors the pre-fix gcraLuaScript: TAT tracked as a single
+// nanosecond-epoch float64, persisted via Lua's tostring (default %.14g).
+func oldGCRADecision(nowNs, tatNs float64, hasTat bool, rate, burst float64) (allowed bool, remaining int, newTatNs float64) {
+	emission := 1e9 / rate
+	delay := emission * burst
+	if !hasTat {
+		tatNs = nowNs
+	}
+
+	newTat := math.Max(nowNs, tatNs) + emission
+	allowAt := newTat - delay
+	remaining = int(math.Floor((delay - (newTat - nowNs)) / emission))
+
+	if nowNs >= allowAt {
+		return true, remaining, roundTrip14g(newTat)
+	}
+	return false, remaining, tatNs
+}
+
+// roundTrip14g mimics persisting a float64 via Lua's tostring/tonumber,
+// which formats with the default %.14g (14 significant digits).
+func roundTrip14g(f float64) float64 {
+	v, _ := strconv.ParseFloat(strconv.FormatFloat(f, 'g', 14, 64), 64)
+	return v
+}
+
+// tsPair mirrors gcraTimeHelpers' (seconds, nanosecond-remainder) encoding.
+type tsPair struct{ sec, nsec float64 }
+
+func splitNs(ns float64) tsPair {
+	sec := math.Floor(ns / 1e9)
+	return tsPair{sec: sec, nsec: ns - sec*1e9}
+}
+
+func tsCmp(a, b tsPair) int {
+	if a.sec != b.sec {
+		if a.sec > b.sec {
+			return 1
+		}
+		return -1
+	}
+	switch {
+	case a.nsec > b.nsec:
+		return 1
+	case a.nsec < b.nsec:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func tsAddNs(t tsPair, deltaNs float64) tsPair {
+	total := t.nsec + deltaNs
+	carry := math.Floor(total / 1e9)
+	rem := total - carry*1e9
+	if rem < 0 {
+		carry--
+		rem += 1e9
+	}
+	return tsPair{sec: t.sec + carry, nsec: math.Floor(rem + 0.5)}
+}
+
+func tsDiffNs(a, b tsPair) float64 {
+	return (a.sec-b.sec)*1e9 + (a.nsec - b.nsec)
+}
+
+// newGCRADecision mirrors the fixed gcraLuaScript/gcraTimeHelpers.
+func newGCRADecision(now, tat tsPair, hasTat bool, rate, burst float64) (allowed bool, remaining int, newTat tsPair) {
+	emission := 1e9 / rate
+	delay := emission * burst
+	if !hasTat {
+		tat = now
+	}
+
+	base := tat
+	if tsCmp(now, tat) > 0 {
+		base = now
+	}
+
+	newTat = tsAddNs(base, emission)
+	allowAt := tsAddNs(newTat, -delay)
+	remaining = int(math.Floor((delay - tsDiffNs(newTat, now)) / emission))
+
+	if tsCmp(now, allowAt) >= 0 {
+		return true, remaining, newTat
+	}
+	return false, remaining, tat
+}
+
+// floorDivInt64 divides like Lua's math.floor(a/b) (rounds toward negative
+// infinity), unlike Go's native integer division (truncates toward zero).
+func floorDivInt64(a, b int64) int64 {
+	q := a / b
+	if r := a % b; r != 0 && (r < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// exactGCRADecision is a third reference implementation, independent of both
+// gcraLuaScript encodings: it tracks now/TAT as plain int64 nanoseconds.
+// int64 holds a Unix-nanosecond timestamp (~1.77e18) exactly (int64 maxes
+// out around 9.2e18), so unlike either Lua encoding above this one can't
+// lose precision — it's the ground truth the other two are checked against.
+func exactGCRADecision(nowNs, tatNs int64, hasTat bool, emissionNs, delayNs int64) (allowed bool, remaining int, newTatNs int64) {
+	if !hasTat {
+		tatNs = nowNs
+	}
+
+	base := tatNs
+	if nowNs > base {
+		base = nowNs
+	}
+
+	newTat := base + emissionNs
+	allowAt := newTat - delayNs
+	remaining = int(floorDivInt64(delayNs-(newTat-nowNs), emissionNs))
+
+	if nowNs >= allowAt {
+		return true, remaining, newTat
+	}
+	return false, remaining, tatNs
+}
+
+// TestGCRATATPrecisionMatchesExactReference is the reproducible harness
+// behind the TAT-encoding fix: it replays many simulated call traces, at
+// today's Unix-nanosecond epoch magnitude, through the fixed (seconds,
+// nanosecond-remainder) pair and an int64-nanosecond reference that can't
+// lose precision, and asserts the two never disagree. It also records how
+// often the pre-fix single-float encoding would have disagreed with that
+// same reference, which is the actual bug this pair of commits fixes —
+// expected to be a small but nonzero fraction of calls, not zero, since the
+// whole point of the fix is that the old encoding occasionally got the
+// allow/deny boundary wrong.
+func TestGCRATATPrecisionMatchesExactReference(t *testing.T) {
+	const (
+		rate        = 5.0
+		burst       = 10.0
+		emissionNs  = int64(1e9 / rate)
+		delayNs     = emissionNs * int64(burst)
+		baseEpochNs = 1.774e18 // ~ current Unix-nanosecond epoch magnitude
+		numCalls    = 200_000
+	)
+
+	rng := rand.New(rand.NewSource(1))
+
+	var exactTatNs, oldTatNs int64
+	var newTat tsPair
+	var exactHas, oldHas, newHas bool
+
+	var elapsedNs float64
+	newMismatches, oldMismatches := 0, 0
+	for i := 0; i < numCalls; i++ {
+		elapsedNs += rng.ExpFloat64() / (rate / 0.7) * 1e9
+		nowNs := baseEpochNs + elapsedNs
+		nowInt := int64(nowNs)
+
+		exactAllowed, exactRemaining, nextExactTat := exactGCRADecision(nowInt, exactTatNs, exactHas, emissionNs, delayNs)
+		exactTatNs, exactHas = nextExactTat, true
+
+		newAllowed, newRemaining, nextNewTat := newGCRADecision(splitNs(nowNs), newTat, newHas, rate, burst)
+		newTat, newHas = nextNewTat, true
+		if newAllowed != exactAllowed || newRemaining != exactRemaining {
+			newMismatches++
+			if newMismatches <= 5 {
+				t.Logf("new-vs-exact mismatch at call %d: exact(allowed=%v remaining=%d) new(allowed=%v remaining=%d)",
+					i, exactAllowed, exactRemaining, newAllowed, newRemaining)
+			}
+		}
+
+		oldAllowed, oldRemaining, nextOldTat := oldGCRADecision(nowNs, float64(oldTatNs), oldHas, rate, burst)
+		oldTatNs, oldHas = int64(nextOldTat), true
+		if oldAllowed != exactAllowed || oldRemaining != exactRemaining {
+			oldMismatches++
+		}
+	}
+
+	t.Logf("pre-fix single-float encoding disagreed with the exact reference on %d/%d calls", oldMismatches, numCalls)
+	if newMismatches > 0 {
+		t.Fatalf("%d/%d calls disagreed between the fixed sec:nsec encoding and the exact int64 reference", newMismatches, numCalls)
+	}
+	if oldMismatches == 0 {
+		t.Fatalf("expected the pre-fix encoding to disagree with the exact reference at least once at this epoch magnitude (that disagreement is the bug being fixed); got 0")
+	}
+}
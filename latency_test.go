@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// latencyReservoirSize bounds the number of samples kept per worker. Reservoir
+// sampling keeps memory flat regardless of request volume while preserving a
+// uniform sample of the full latency distribution, which is enough resolution
+// for p50/p95/p99/p999 once all workers' reservoirs are merged.
+const latencyReservoirSize = 8192
+
+// latencyReservoir is a per-worker, lock-free (single-writer) reservoir sample
+// of af(ctx, key) call durations. It is never shared across goroutines while
+// being written to, so no synchronization is needed until the merge after
+// wg.Wait().
+type latencyReservoir struct {
+	rng     *rand.Rand
+	samples []time.Duration
+	seen    int64
+}
+
+func newLatencyReservoir(seed int64) *latencyReservoir {
+	return &latencyReservoir{
+		rng:     rand.New(rand.NewSource(seed)),
+		samples: make([]time.Duration, 0, latencyReservoirSize),
+	}
+}
+
+func (r *latencyReservoir) record(d time.Duration) {
+	r.seen++
+	if len(r.samples) < cap(r.samples) {
+		r.samples = append(r.samples, d)
+		return
+	}
+	if j := r.rng.Int63n(r.seen); j < int64(len(r.samples)) {
+		r.samples[j] = d
+	}
+}
+
+// mergeLatencySamples pools every worker's reservoir into one sorted slice
+// percentiles can be read off directly.
+func mergeLatencySamples(reservoirs []*latencyReservoir) []time.Duration {
+	n := 0
+	for _, r := range reservoirs {
+		n += len(r.samples)
+	}
+	merged := make([]time.Duration, 0, n)
+	for _, r := range reservoirs {
+		merged = append(merged, r.samples...)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i] < merged[j] })
+	return merged
+}
+
+// latencyPercentile returns the value at percentile p (0..1) of an
+// already-sorted slice. Returns 0 for an empty slice.
+func latencyPercentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p * float64(len(sorted)-1))
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// atomicMaxDuration CAS-loops a running max into dst, mirroring the maxHeap
+// sampler above; the reservoir alone can't be trusted to retain the true max
+// since outliers are the most likely samples to get evicted.
+func atomicMaxDuration(dst *int64, d time.Duration) {
+	for {
+		old := atomic.LoadInt64(dst)
+		if int64(d) <= old {
+			return
+		}
+		if atomic.CompareAndSwapInt64(dst, old, int64(d)) {
+			return
+		}
+	}
+}
+
+// writeLatencyCSV appends one row per (impl, scenario) to path, truncating
+// any previous contents, so runs can be diffed across commits with e.g.
+// `git diff` or a spreadsheet.
+func writeLatencyCSV(path string, rows []comparisonRow) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("writeLatencyCSV: create %q: %w", path, err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	header := []string{
+		"impl", "scenario", "total", "allowed", "denied", "errs",
+		"req_per_sec", "p50_us", "p95_us", "p99_us", "p999_us", "max_us",
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, r := range rows {
+		reqPerSec := float64(r.stats.total) / r.stats.duration.Seconds()
+		record := []string{
+			r.impl,
+			r.scenario,
+			fmt.Sprintf("%d", r.stats.total),
+			fmt.Sprintf("%d", r.stats.allowed),
+			fmt.Sprintf("%d", r.stats.denied),
+			fmt.Sprintf("%d", r.stats.errs),
+			fmt.Sprintf("%.1f", reqPerSec),
+			fmt.Sprintf("%.1f", r.stats.p50.Seconds()*1e6),
+			fmt.Sprintf("%.1f", r.stats.p95.Seconds()*1e6),
+			fmt.Sprintf("%.1f", r.stats.p99.Seconds()*1e6),
+			fmt.Sprintf("%.1f", r.stats.p999.Seconds()*1e6),
+			fmt.Sprintf("%.1f", r.stats.maxLatency.Seconds()*1e6),
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	return w.Error()
+}
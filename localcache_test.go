@@ -0,0 +1,231 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// NOTE: the request this implements asked for a `ratelimit.WithLocalCache`
+// option on `ratelimit.NewRedisRateLimiter` itself (borrowing the layered
+// local-cache-in-front-of-Redis idea from Mattermost/Harbor), including a
+// "positive" path where well-funded keys get decremented locally and
+// flushed to Redis on a timer. That constructor lives in
+// github.com/moneyforward/mf-common-go, a dependency of this repo, not code
+// this repo owns or vendors — there's no source here to add an option to.
+//
+// Scope cut: this decorator only implements the negative-cache half (once a
+// key is denied, repeat callers are answered locally for a while). The
+// positive half is deliberately left out of this commit rather than shipped
+// half-verified: admitting locally without Redis ever finding out means
+// those admits are invisible to its token accounting, which is exactly the
+// over-admission risk a local cache like this needs to get right, and
+// getting the flush-on-a-timer reconciliation correct is bigger than a
+// drive-by in a benchmark harness. newLocalCacheAllow can only ever
+// under-admit relative to Redis, never over-admit, so
+// TestLocalCacheAllow_DeniesConverge below is a regression guard (the
+// decorator doesn't break global correctness), not proof the harder,
+// unimplemented half would be safe.
+
+// lruDenyCache is a fixed-size, TTL'd LRU of denied limiter keys. While a key
+// is present, calls for it are answered locally as denied without a Redis
+// round trip.
+type lruDenyCache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type denyEntry struct {
+	key      string
+	deniedAt time.Time
+}
+
+func newLRUDenyCache(size int, ttl time.Duration) *lruDenyCache {
+	return &lruDenyCache{
+		size:  size,
+		ttl:   ttl,
+		ll:    list.New(),
+		items: make(map[string]*list.Element, size),
+	}
+}
+
+func (c *lruDenyCache) markDenied(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		el.Value.(*denyEntry).deniedAt = time.Now()
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	el := c.ll.PushFront(&denyEntry{key: key, deniedAt: time.Now()})
+	c.items[key] = el
+	if c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*denyEntry).key)
+		}
+	}
+}
+
+// stillDenied reports whether key is cached as denied and hasn't aged out of
+// the TTL window yet.
+func (c *lruDenyCache) stillDenied(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	if time.Since(el.Value.(*denyEntry).deniedAt) >= c.ttl {
+		c.ll.Remove(el)
+		delete(c.items, key)
+		return false
+	}
+	c.ll.MoveToFront(el)
+	return true
+}
+
+func (c *lruDenyCache) clear(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.ll.Remove(el)
+		delete(c.items, key)
+	}
+}
+
+// newLocalCacheAllow fronts af with an in-process LRU of recently-denied
+// keys: once a key is denied, repeat callers get allowed=false from memory
+// for up to negativeTTL before the next call is let through to Redis again.
+func newLocalCacheAllow(af allowFunc, size int, negativeTTL time.Duration) allowFunc {
+	cache := newLRUDenyCache(size, negativeTTL)
+	return func(ctx context.Context, key string) (bool, int, error) {
+		if cache.stillDenied(key) {
+			return false, 0, nil
+		}
+
+		allowed, remaining, err := af(ctx, key)
+		if err != nil {
+			return allowed, remaining, err
+		}
+		if allowed {
+			cache.clear(key)
+		} else {
+			cache.markDenied(key)
+		}
+		return allowed, remaining, nil
+	}
+}
+
+// hammerAllow calls af as fast as it'll go for duration, counting allowed
+// calls into *allowed.
+func hammerAllow(ctx context.Context, af allowFunc, key string, duration time.Duration, allowed *int64) {
+	deadline := time.Now().Add(duration)
+	for time.Now().Before(deadline) {
+		ok, _, err := af(ctx, key)
+		if err != nil {
+			return
+		}
+		if ok {
+			atomic.AddInt64(allowed, 1)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestLocalCacheAllow_DeniesConverge checks that fronting a limiter with
+// newLocalCacheAllow doesn't change the globally enforced rate: two
+// "processes" (independent decorator instances, independent local caches)
+// hammering the same Redis-backed limiter key should still see the global
+// rate enforced across both of them combined, within a generous tolerance
+// for burst capacity and local negative-cache staleness. This can only catch
+// a bug that makes the decorator over-deny (e.g. never clearing a stale
+// negative entry); see the NOTE above the decorator for why it can't catch
+// over-admission.
+func TestLocalCacheAllow_DeniesConverge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping load-bearing convergence check in -short mode")
+	}
+
+	rdb := newRedisClient()
+	t.Cleanup(func() { _ = rdb.Close() })
+	requireRedis(t, rdb)
+
+	const (
+		rate     = 10 // tokens/sec
+		capacity = 10
+	)
+	duration := 3 * time.Second
+	prefix := uniquePrefix(t)
+	key := "user:shared"
+
+	newProcess := func() allowFunc {
+		af := newMFCommonAllow(t, rdb, prefix, rate, capacity, time.Minute)
+		return newLocalCacheAllow(af, 1000, 200*time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), duration+5*time.Second)
+	defer cancel()
+
+	var allowedA, allowedB int64
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); hammerAllow(ctx, newProcess(), key, duration, &allowedA) }()
+	go func() { defer wg.Done(); hammerAllow(ctx, newProcess(), key, duration, &allowedB) }()
+	wg.Wait()
+
+	total := allowedA + allowedB
+	want := float64(rate) * duration.Seconds()
+	// Generous tolerance: burst capacity plus whatever the negative-cache
+	// window lets slip past before it catches up with Redis.
+	tolerance := want*0.5 + float64(capacity)
+	if diff := math.Abs(float64(total) - want); diff > tolerance {
+		t.Fatalf("global allowed=%d (A=%d B=%d), want ~%.0f (+/- %.0f)", total, allowedA, allowedB, want, tolerance)
+	}
+}
+
+// TestLRUDenyCache_ExpiresAndEvicts is the load-bearing correctness check for
+// the negative-cache half itself, in-process and Redis-free: a denied key
+// must stop being reported as denied once its TTL has passed (otherwise
+// newLocalCacheAllow would over-deny forever), and the cache must not grow
+// past its configured size (otherwise it's not an LRU).
+func TestLRUDenyCache_ExpiresAndEvicts(t *testing.T) {
+	c := newLRUDenyCache(2, 50*time.Millisecond)
+
+	c.markDenied("a")
+	if !c.stillDenied("a") {
+		t.Fatalf("expected %q to be denied immediately after markDenied", "a")
+	}
+
+	time.Sleep(75 * time.Millisecond)
+	if c.stillDenied("a") {
+		t.Fatalf("expected %q to have expired after its TTL", "a")
+	}
+
+	c.markDenied("a")
+	c.markDenied("b")
+	c.markDenied("c") // over capacity 2: evicts the least recently used entry ("a").
+	if c.stillDenied("a") {
+		t.Fatalf("expected %q to be evicted once the cache exceeded its size", "a")
+	}
+	if !c.stillDenied("b") || !c.stillDenied("c") {
+		t.Fatalf("expected %q and %q to remain after eviction", "b", "c")
+	}
+
+	c.clear("b")
+	if c.stillDenied("b") {
+		t.Fatalf("expected %q to be gone after clear", "b")
+	}
+}
@@ -24,17 +24,11 @@ func newLimiter(t *testing.T, rdb *redis.Client, prefix string, ratePerSec, capa
 	return limiter
 }
 
-func TestRedisRateLimiter_BurstThenDeny(t *testing.T) {
-	rdb := newRedisClient()
-	t.Cleanup(func() { _ = rdb.Close() })
-	requireRedis(t, rdb)
-
-	const (
-		rate     = 1
-		capacity = 3
-	)
-	limiter := newLimiter(t, rdb, uniquePrefix(t), rate, capacity, 5*time.Second)
-
+// assertBurstThenDeny drives capacity Allow calls through and expects every
+// one of them allowed, then expects the next call denied. Shared by the
+// single-node and Redis Cluster suites.
+func assertBurstThenDeny(t *testing.T, limiter ratelimit.RateLimiter, capacity int) {
+	t.Helper()
 	ctx := context.Background()
 	key := "user:burst"
 
@@ -60,17 +54,23 @@ func TestRedisRateLimiter_BurstThenDeny(t *testing.T) {
 	}
 }
 
-func TestRedisRateLimiter_RefillsOverTime(t *testing.T) {
+func TestRedisRateLimiter_BurstThenDeny(t *testing.T) {
 	rdb := newRedisClient()
 	t.Cleanup(func() { _ = rdb.Close() })
 	requireRedis(t, rdb)
 
 	const (
-		rate     = 2 // tokens/sec
-		capacity = 2
+		rate     = 1
+		capacity = 3
 	)
 	limiter := newLimiter(t, rdb, uniquePrefix(t), rate, capacity, 5*time.Second)
+	assertBurstThenDeny(t, limiter, capacity)
+}
 
+// assertRefillsOverTime exhausts the burst, confirms a deny, then waits for
+// one refill interval and confirms the next call is allowed again.
+func assertRefillsOverTime(t *testing.T, limiter ratelimit.RateLimiter, capacity int) {
+	t.Helper()
 	ctx := context.Background()
 	key := "user:refill"
 
@@ -102,17 +102,23 @@ func TestRedisRateLimiter_RefillsOverTime(t *testing.T) {
 	}
 }
 
-func TestRedisRateLimiter_IsolatedPerKey(t *testing.T) {
+func TestRedisRateLimiter_RefillsOverTime(t *testing.T) {
 	rdb := newRedisClient()
 	t.Cleanup(func() { _ = rdb.Close() })
 	requireRedis(t, rdb)
 
 	const (
-		rate     = 1
+		rate     = 2 // tokens/sec
 		capacity = 2
 	)
 	limiter := newLimiter(t, rdb, uniquePrefix(t), rate, capacity, 5*time.Second)
+	assertRefillsOverTime(t, limiter, capacity)
+}
 
+// assertIsolatedPerKey exhausts keyA and confirms keyB, sharing the same
+// limiter, still has its own fresh bucket.
+func assertIsolatedPerKey(t *testing.T, limiter ratelimit.RateLimiter, capacity int) {
+	t.Helper()
 	ctx := context.Background()
 	keyA := "user:A"
 	keyB := "user:B"
@@ -145,6 +151,19 @@ func TestRedisRateLimiter_IsolatedPerKey(t *testing.T) {
 	}
 }
 
+func TestRedisRateLimiter_IsolatedPerKey(t *testing.T) {
+	rdb := newRedisClient()
+	t.Cleanup(func() { _ = rdb.Close() })
+	requireRedis(t, rdb)
+
+	const (
+		rate     = 1
+		capacity = 2
+	)
+	limiter := newLimiter(t, rdb, uniquePrefix(t), rate, capacity, 5*time.Second)
+	assertIsolatedPerKey(t, limiter, capacity)
+}
+
 func TestRedisRateLimiter_IsolatedPerPrefix(t *testing.T) {
 	rdb := newRedisClient()
 	t.Cleanup(func() { _ = rdb.Close() })
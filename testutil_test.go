@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -21,6 +22,43 @@ func newRedisClient() *redis.Client {
 	return redis.NewClient(&redis.Options{Addr: redisAddr()})
 }
 
+// redisClusterAddrs returns the comma-separated REDIS_CLUSTER_ADDRS seed list,
+// or nil if the cluster isn't configured for this run.
+func redisClusterAddrs() []string {
+	v := strings.TrimSpace(os.Getenv("REDIS_CLUSTER_ADDRS"))
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+func newRedisClusterClient() *redis.ClusterClient {
+	return redis.NewClusterClient(&redis.ClusterOptions{Addrs: redisClusterAddrs()})
+}
+
+// requireRedisCluster skips the test when REDIS_CLUSTER_ADDRS isn't set, or
+// when the cluster isn't reachable, analogous to requireRedis.
+func requireRedisCluster(t testing.TB, rdb *redis.ClusterClient) {
+	t.Helper()
+	if len(redisClusterAddrs()) == 0 {
+		t.Skip("REDIS_CLUSTER_ADDRS not set; skipping Redis Cluster tests")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	deadline := time.Now().Add(2 * time.Second)
+	var lastErr error
+	for time.Now().Before(deadline) {
+		lastErr = rdb.Ping(ctx).Err()
+		if lastErr == nil {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Skipf("redis cluster not reachable at %q: %v", redisClusterAddrs(), lastErr)
+}
+
 func requireRedis(t testing.TB, rdb *redis.Client) {
 	t.Helper()
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)